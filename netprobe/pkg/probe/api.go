@@ -0,0 +1,79 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SchedulerAPI exposes a Scheduler over HTTP so the agent platform can list
+// monitored targets, pull their history, and subscribe to recent events:
+//
+//	GET /probes              -> []string target IDs
+//	GET /probes/{id}/history -> []HistoryEntry for that target
+//	GET /events              -> []Event recently observed across all targets
+type SchedulerAPI struct {
+	scheduler *Scheduler
+}
+
+// NewSchedulerAPI wraps scheduler in an http.Handler.
+func NewSchedulerAPI(scheduler *Scheduler) *SchedulerAPI {
+	return &SchedulerAPI{scheduler: scheduler}
+}
+
+func (a *SchedulerAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/probes":
+		a.handleProbes(w, r)
+	case strings.HasPrefix(r.URL.Path, "/probes/") && strings.HasSuffix(r.URL.Path, "/history"):
+		a.handleHistory(w, r)
+	case r.URL.Path == "/events":
+		a.handleEvents(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (a *SchedulerAPI) handleProbes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, a.scheduler.Targets())
+}
+
+func (a *SchedulerAPI) handleHistory(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/probes/"), "/history")
+	if id == "" {
+		http.Error(w, "missing target id", http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	entries, err := a.scheduler.store.History(id, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, entries)
+}
+
+func (a *SchedulerAPI) handleEvents(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, a.scheduler.RecentEvents())
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+}
@@ -0,0 +1,157 @@
+package probe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// evaluateAssertions checks every opts.Assertions entry against the
+// response and returns one AssertionResult per assertion, in order.
+func evaluateAssertions(opts HTTPOptions, resp *http.Response, body []byte, latency time.Duration, tlsInfo map[string]any) []AssertionResult {
+	results := make([]AssertionResult, 0, len(opts.Assertions))
+	for _, a := range opts.Assertions {
+		results = append(results, evaluateAssertion(a, resp, body, latency, tlsInfo))
+	}
+	return results
+}
+
+func evaluateAssertion(a Assertion, resp *http.Response, body []byte, latency time.Duration, tlsInfo map[string]any) AssertionResult {
+	switch a.Type {
+	case "status_in":
+		observed := strconv.Itoa(resp.StatusCode)
+		for _, code := range strings.Split(a.Value, ",") {
+			if strings.TrimSpace(code) == observed {
+				return AssertionResult{Type: a.Type, Pass: true, Observed: observed}
+			}
+		}
+		return AssertionResult{Type: a.Type, Observed: observed, Error: fmt.Sprintf("status %s not in [%s]", observed, a.Value)}
+
+	case "header_equals":
+		observed := resp.Header.Get(a.Header)
+		if observed == a.Value {
+			return AssertionResult{Type: a.Type, Pass: true, Observed: observed}
+		}
+		return AssertionResult{Type: a.Type, Observed: observed, Error: fmt.Sprintf("header %q: expected %q, got %q", a.Header, a.Value, observed)}
+
+	case "header_matches":
+		observed := resp.Header.Get(a.Header)
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			return AssertionResult{Type: a.Type, Observed: observed, Error: fmt.Sprintf("invalid regexp %q: %v", a.Value, err)}
+		}
+		if re.MatchString(observed) {
+			return AssertionResult{Type: a.Type, Pass: true, Observed: observed}
+		}
+		return AssertionResult{Type: a.Type, Observed: observed, Error: fmt.Sprintf("header %q value %q does not match %q", a.Header, observed, a.Value)}
+
+	case "body_regex":
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			return AssertionResult{Type: a.Type, Error: fmt.Sprintf("invalid regexp %q: %v", a.Value, err)}
+		}
+		if re.Match(body) {
+			return AssertionResult{Type: a.Type, Pass: true, Observed: "matched"}
+		}
+		return AssertionResult{Type: a.Type, Observed: "no match", Error: fmt.Sprintf("body does not match %q", a.Value)}
+
+	case "body_jsonpath":
+		observed, err := jsonPathLookup(body, a.Path)
+		if err != nil {
+			return AssertionResult{Type: a.Type, Error: err.Error()}
+		}
+		if observed == a.Value {
+			return AssertionResult{Type: a.Type, Pass: true, Observed: observed}
+		}
+		return AssertionResult{Type: a.Type, Observed: observed, Error: fmt.Sprintf("%s: expected %q, got %q", a.Path, a.Value, observed)}
+
+	case "latency_below_ms":
+		thresholdMs, err := strconv.ParseFloat(a.Value, 64)
+		if err != nil {
+			return AssertionResult{Type: a.Type, Error: fmt.Sprintf("invalid threshold %q: %v", a.Value, err)}
+		}
+		observedMs := float64(latency.Milliseconds())
+		observed := strconv.FormatFloat(observedMs, 'f', -1, 64)
+		if observedMs < thresholdMs {
+			return AssertionResult{Type: a.Type, Pass: true, Observed: observed}
+		}
+		return AssertionResult{Type: a.Type, Observed: observed, Error: fmt.Sprintf("latency %sms not below %sms", observed, a.Value)}
+
+	case "tls_expires_after_days":
+		thresholdDays, err := strconv.ParseFloat(a.Value, 64)
+		if err != nil {
+			return AssertionResult{Type: a.Type, Error: fmt.Sprintf("invalid threshold %q: %v", a.Value, err)}
+		}
+		notAfter, ok := tlsInfo["not_after"].(time.Time)
+		if !ok {
+			return AssertionResult{Type: a.Type, Error: "no TLS connection state available"}
+		}
+		daysLeft := time.Until(notAfter).Hours() / 24
+		observed := strconv.FormatFloat(daysLeft, 'f', 1, 64)
+		if daysLeft > thresholdDays {
+			return AssertionResult{Type: a.Type, Pass: true, Observed: observed}
+		}
+		return AssertionResult{Type: a.Type, Observed: observed, Error: fmt.Sprintf("certificate expires in %s days, not after %s", observed, a.Value)}
+
+	case "body_sha256_equals":
+		sum := sha256.Sum256(body)
+		observed := hex.EncodeToString(sum[:])
+		if strings.EqualFold(observed, a.Value) {
+			return AssertionResult{Type: a.Type, Pass: true, Observed: observed}
+		}
+		return AssertionResult{Type: a.Type, Observed: observed, Error: fmt.Sprintf("body sha256 %s != expected %s", observed, a.Value)}
+
+	default:
+		return AssertionResult{Type: a.Type, Error: fmt.Sprintf("unknown assertion type %q", a.Type)}
+	}
+}
+
+// jsonPathLookup resolves a dot-separated path (object keys or array
+// indices, e.g. "data.items.0.id") against a JSON body and stringifies
+// whatever it finds.
+func jsonPathLookup(body []byte, path string) (string, error) {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return "", fmt.Errorf("body is not valid JSON: %w", err)
+	}
+
+	cur := doc
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			continue
+		}
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[segment]
+			if !ok {
+				return "", fmt.Errorf("jsonpath %q: key %q not found", path, segment)
+			}
+			cur = v
+		case []any:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return "", fmt.Errorf("jsonpath %q: invalid array index %q", path, segment)
+			}
+			cur = node[idx]
+		default:
+			return "", fmt.Errorf("jsonpath %q: cannot descend into %q", path, segment)
+		}
+	}
+
+	switch v := cur.(type) {
+	case string:
+		return v, nil
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("jsonpath %q: %w", path, err)
+		}
+		return string(encoded), nil
+	}
+}
@@ -0,0 +1,86 @@
+package probe
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestJSONPathLookup(t *testing.T) {
+	body := []byte(`{"data":{"items":[{"id":"a1"},{"id":"a2"}]},"ok":true}`)
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{path: "data.items.0.id", want: "a1"},
+		{path: "data.items.1.id", want: "a2"},
+		{path: "ok", want: "true"},
+	}
+	for _, tc := range cases {
+		got, err := jsonPathLookup(body, tc.path)
+		if err != nil {
+			t.Fatalf("jsonPathLookup(%q) returned error: %v", tc.path, err)
+		}
+		if got != tc.want {
+			t.Errorf("jsonPathLookup(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestJSONPathLookupErrors(t *testing.T) {
+	body := []byte(`{"data":{"items":[1,2]}}`)
+
+	cases := []string{
+		"missing.key",
+		"data.items.5",
+		"data.items.notanumber",
+	}
+	for _, path := range cases {
+		if _, err := jsonPathLookup(body, path); err == nil {
+			t.Errorf("jsonPathLookup(%q) expected an error, got nil", path)
+		}
+	}
+
+	if _, err := jsonPathLookup([]byte("not json"), "a"); err == nil {
+		t.Error("jsonPathLookup on invalid JSON expected an error, got nil")
+	}
+}
+
+func TestEvaluateAssertionStatusIn(t *testing.T) {
+	resp := &http.Response{StatusCode: 204}
+	result := evaluateAssertion(Assertion{Type: "status_in", Value: "200,204"}, resp, nil, 0, nil)
+	if !result.Pass {
+		t.Errorf("expected status_in to pass, got %+v", result)
+	}
+
+	result = evaluateAssertion(Assertion{Type: "status_in", Value: "200,201"}, resp, nil, 0, nil)
+	if result.Pass {
+		t.Errorf("expected status_in to fail for 204 not in [200,201], got %+v", result)
+	}
+}
+
+func TestEvaluateAssertionBodySHA256(t *testing.T) {
+	body := []byte("hello world")
+	// sha256("hello world")
+	const want = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+	result := evaluateAssertion(Assertion{Type: "body_sha256_equals", Value: want}, &http.Response{}, body, 0, nil)
+	if !result.Pass {
+		t.Errorf("expected body_sha256_equals to pass, got %+v", result)
+	}
+
+	result = evaluateAssertion(Assertion{Type: "body_sha256_equals", Value: "deadbeef"}, &http.Response{}, body, 0, nil)
+	if result.Pass {
+		t.Errorf("expected body_sha256_equals to fail for a mismatching hash, got %+v", result)
+	}
+}
+
+func TestEvaluateAssertionUnknownType(t *testing.T) {
+	result := evaluateAssertion(Assertion{Type: "not_a_real_type"}, &http.Response{}, nil, 0, nil)
+	if result.Pass {
+		t.Error("expected an unknown assertion type to never pass")
+	}
+	if result.Error == "" {
+		t.Error("expected an unknown assertion type to report an error")
+	}
+}
@@ -0,0 +1,79 @@
+package probe
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// countingReader wraps an io.Reader and tracks how many bytes have passed
+// through it, so HTTPProbe can report the on-wire size of a response body
+// even when it's been transparently decompressed.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decodingReader wraps raw in a gzip/brotli reader when the response
+// declares a matching Content-Encoding and the caller asked for decoding.
+// Unknown or absent encodings, and encodings the caller didn't ask to
+// decode, pass raw through unchanged.
+func decodingReader(resp *http.Response, raw io.Reader, decode bool) (io.Reader, error) {
+	if !decode {
+		return raw, nil
+	}
+	switch encoding := strings.ToLower(resp.Header.Get("Content-Encoding")); {
+	case strings.Contains(encoding, "gzip"):
+		return gzip.NewReader(raw)
+	case strings.Contains(encoding, "br"):
+		return brotli.NewReader(raw), nil
+	default:
+		return raw, nil
+	}
+}
+
+// readBody drains resp.Body (decoding it first if requested), returning the
+// full decoded body, a snippet capped at maxBytes (maxBytes == -1 keeps the
+// whole body), and the number of bytes actually read off the wire before
+// decoding. If savePath is set, the full decoded body is also written there.
+func readBody(resp *http.Response, maxBytes int, decode bool, savePath string) (full, snippet []byte, onWireBytes int64, err error) {
+	raw := &countingReader{r: resp.Body}
+	reader, err := decodingReader(resp, raw, decode)
+	if err != nil {
+		return nil, nil, raw.n, err
+	}
+
+	full, err = io.ReadAll(reader)
+	onWireBytes = raw.n
+	if err != nil {
+		return full, nil, onWireBytes, err
+	}
+
+	if savePath != "" {
+		if writeErr := os.WriteFile(savePath, full, 0o644); writeErr != nil {
+			return full, nil, onWireBytes, writeErr
+		}
+	}
+
+	limit := maxBytes
+	if limit == 0 {
+		limit = 4096
+	}
+	if limit < 0 || limit > len(full) {
+		snippet = full
+	} else {
+		snippet = full[:limit]
+	}
+
+	return full, snippet, onWireBytes, nil
+}
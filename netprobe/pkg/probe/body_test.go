@@ -0,0 +1,98 @@
+package probe
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(data)); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodingReaderGzip(t *testing.T) {
+	const want = "hello, probe"
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:   io.NopCloser(bytes.NewReader(gzipBytes(t, want))),
+	}
+
+	reader, err := decodingReader(resp, resp.Body, true)
+	if err != nil {
+		t.Fatalf("decodingReader returned error: %v", err)
+	}
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("reading decoded body: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("decoded body = %q, want %q", got, want)
+	}
+}
+
+func TestDecodingReaderPassthroughWhenNotRequested(t *testing.T) {
+	raw := gzipBytes(t, "still compressed")
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:   io.NopCloser(bytes.NewReader(raw)),
+	}
+
+	reader, err := decodingReader(resp, resp.Body, false)
+	if err != nil {
+		t.Fatalf("decodingReader returned error: %v", err)
+	}
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("reading passthrough body: %v", err)
+	}
+	if !bytes.Equal(got, raw) {
+		t.Error("expected decodingReader(decode=false) to pass the raw bytes through unchanged")
+	}
+}
+
+func TestReadBodySnippetTruncation(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{},
+		Body:   io.NopCloser(bytes.NewReader([]byte("0123456789"))),
+	}
+
+	full, snippet, onWire, err := readBody(resp, 4, false, "")
+	if err != nil {
+		t.Fatalf("readBody returned error: %v", err)
+	}
+	if string(full) != "0123456789" {
+		t.Errorf("full = %q, want the entire body", full)
+	}
+	if string(snippet) != "0123" {
+		t.Errorf("snippet = %q, want %q", snippet, "0123")
+	}
+	if onWire != 10 {
+		t.Errorf("onWireBytes = %d, want 10", onWire)
+	}
+}
+
+func TestReadBodyUnlimited(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{},
+		Body:   io.NopCloser(bytes.NewReader([]byte("0123456789"))),
+	}
+
+	_, snippet, _, err := readBody(resp, -1, false, "")
+	if err != nil {
+		t.Fatalf("readBody returned error: %v", err)
+	}
+	if string(snippet) != "0123456789" {
+		t.Errorf("snippet = %q, want the entire body when maxBytes is -1", snippet)
+	}
+}
@@ -2,15 +2,123 @@ package probe
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptrace"
 	"strings"
 	"time"
+
+	"golang.org/x/net/http2"
 )
 
+// buildTransport turns opts.TLS and opts.ForceHTTP into an *http.Transport.
+// It's kept separate from HTTPProbe so the TLS/H2 wiring can be unit tested
+// without issuing a real request.
+func buildTransport(opts HTTPOptions) (*http.Transport, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: opts.TLS.InsecureSkipVerify,
+		ServerName:         opts.TLS.ServerName,
+		MinVersion:         opts.TLS.MinVersion,
+		MaxVersion:         opts.TLS.MaxVersion,
+	}
+
+	if opts.TLS.CACertPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(opts.TLS.CACertPEM)) {
+			return nil, fmt.Errorf("no certificates found in CACertPEM")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.TLS.ClientCertPEM != "" || opts.TLS.ClientKeyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(opts.TLS.ClientCertPEM), []byte(opts.TLS.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("load client keypair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
+		// The stdlib transport otherwise auto-advertises Accept-Encoding:
+		// gzip, transparently decompresses, and strips Content-Encoding
+		// before we ever see the response. That bypasses opts.DecodeBody
+		// and opts.MaxBodyBytes entirely for gzip, so disable it and let
+		// readBody/decodingReader own decompression explicitly.
+		DisableCompression: true,
+	}
+
+	switch opts.ForceHTTP {
+	case "1.1":
+		// Disabling ALPN's h2 advertisement keeps the transport on HTTP/1.1.
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	case "2":
+		if err := http2.ConfigureTransport(transport); err != nil {
+			return nil, fmt.Errorf("configure http2 transport: %w", err)
+		}
+	case "", "auto":
+		// A transport with a custom TLSClientConfig only offers h2 over
+		// ALPN when ForceAttemptHTTP2 is set; with it, ALPN picks h2 or
+		// http/1.1 as the server prefers.
+		transport.ForceAttemptHTTP2 = true
+	default:
+		return nil, fmt.Errorf("unknown ForceHTTP value %q", opts.ForceHTTP)
+	}
+
+	return transport, nil
+}
+
+// tlsConnectionDetails turns a negotiated tls.ConnectionState into the
+// Details fields HTTPProbe reports, and checks it against PinnedSHA256 when
+// the caller asked for certificate pinning.
+func tlsConnectionDetails(opts HTTPOptions, cs tls.ConnectionState) (map[string]any, error) {
+	info := map[string]any{
+		"version":      tlsVersionName(cs.Version),
+		"cipher_suite": tls.CipherSuiteName(cs.CipherSuite),
+		"alpn":         cs.NegotiatedProtocol,
+	}
+
+	if len(cs.PeerCertificates) == 0 {
+		return info, nil
+	}
+
+	leaf := cs.PeerCertificates[0]
+	fingerprint := sha256.Sum256(leaf.Raw)
+	fingerprintHex := hex.EncodeToString(fingerprint[:])
+
+	info["peer_subject"] = leaf.Subject.String()
+	info["peer_sans"] = leaf.DNSNames
+	info["not_before"] = leaf.NotBefore
+	info["not_after"] = leaf.NotAfter
+	info["sha256_fingerprint"] = fingerprintHex
+
+	if opts.TLS.PinnedSHA256 != "" && !strings.EqualFold(opts.TLS.PinnedSHA256, fingerprintHex) {
+		return info, fmt.Errorf("pinned certificate mismatch: expected %s, got %s", opts.TLS.PinnedSHA256, fingerprintHex)
+	}
+
+	return info, nil
+}
+
+func tlsVersionName(v uint16) string {
+	switch v {
+	case tls.VersionTLS10:
+		return "TLS1.0"
+	case tls.VersionTLS11:
+		return "TLS1.1"
+	case tls.VersionTLS12:
+		return "TLS1.2"
+	case tls.VersionTLS13:
+		return "TLS1.3"
+	default:
+		return fmt.Sprintf("unknown(0x%04x)", v)
+	}
+}
+
 func HTTPProbe(opts HTTPOptions) Result {
 	toolName := opts.Tool
 	if toolName == "" {
@@ -45,19 +153,39 @@ func HTTPProbe(opts HTTPOptions) Result {
 
 	var dnsStart, connectStart, tlsHandshakeStart, gotConn, gotFirstByte time.Time
 	var dnsDuration, connectDuration, tlsDuration, waitDuration time.Duration
+	var dnsDoneAt, connectDoneAt, tlsDoneAt time.Time
+	var tlsInfo map[string]any
+	var tlsErr error
+	var hops []HopStat
+
+	// resetHopTiming clears the per-hop timing state so each leg of a
+	// redirect chain (including reused connections, which skip DNS/Connect/
+	// TLS entirely) reports only its own durations rather than the
+	// previous hop's.
+	resetHopTiming := func() {
+		dnsStart, connectStart, tlsHandshakeStart, gotConn, gotFirstByte = time.Time{}, time.Time{}, time.Time{}, time.Time{}, time.Time{}
+		dnsDuration, connectDuration, tlsDuration, waitDuration = 0, 0, 0, 0
+		dnsDoneAt, connectDoneAt, tlsDoneAt = time.Time{}, time.Time{}, time.Time{}
+	}
 
 	trace := &httptrace.ClientTrace{
 		DNSStart: func(dsi httptrace.DNSStartInfo) { dnsStart = time.Now() },
 		DNSDone: func(ddi httptrace.DNSDoneInfo) {
-			dnsDuration = time.Since(dnsStart)
+			dnsDoneAt = time.Now()
+			dnsDuration = dnsDoneAt.Sub(dnsStart)
 		},
 		ConnectStart: func(network, addr string) { connectStart = time.Now() },
 		ConnectDone: func(network, addr string, err error) {
-			connectDuration = time.Since(connectStart)
+			connectDoneAt = time.Now()
+			connectDuration = connectDoneAt.Sub(connectStart)
 		},
 		TLSHandshakeStart: func() { tlsHandshakeStart = time.Now() },
 		TLSHandshakeDone: func(cs tls.ConnectionState, err error) {
-			tlsDuration = time.Since(tlsHandshakeStart)
+			tlsDoneAt = time.Now()
+			tlsDuration = tlsDoneAt.Sub(tlsHandshakeStart)
+			if err == nil {
+				tlsInfo, tlsErr = tlsConnectionDetails(opts, cs)
+			}
 		},
 		GotConn: func(gci httptrace.GotConnInfo) { gotConn = time.Now() },
 		GotFirstResponseByte: func() {
@@ -67,8 +195,43 @@ func HTTPProbe(opts HTTPOptions) Result {
 	}
 	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
 
+	transport, err := buildTransport(opts)
+	if err != nil {
+		return Result{
+			Success: false,
+			Tool:    toolName,
+			URL:     opts.URL,
+			Error:   fmt.Sprintf("build transport failed: %v", err),
+		}
+	}
+
 	client := &http.Client{
-		Timeout: time.Duration(opts.TimeoutSec) * time.Second,
+		Timeout:   time.Duration(opts.TimeoutSec) * time.Second,
+		Transport: transport,
+	}
+
+	// Go's zero-value http.Client (what HTTPProbe used before) follows up to
+	// 10 redirects automatically; preserve that default regardless of
+	// opts.FollowRedirects; req.Response is the response that triggered
+	// this redirect (set by the standard client before calling
+	// CheckRedirect), so this is where we close out the hop that just
+	// finished and start a clean one for the request about to be sent.
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		prev := via[len(via)-1]
+		hops = append(hops, HopStat{
+			URL:                prev.URL.String(),
+			StatusCode:         req.Response.StatusCode,
+			DNSLookupMs:        float64(dnsDuration.Milliseconds()),
+			TCPConnectionMs:    float64(connectDuration.Milliseconds()),
+			TLSHandshakeMs:     float64(tlsDuration.Milliseconds()),
+			ServerProcessingMs: float64(waitDuration.Milliseconds()),
+			ContentTransferMs:  float64(time.Since(gotFirstByte).Milliseconds()),
+		})
+		resetHopTiming()
+		return nil
 	}
 
 	start := time.Now()
@@ -85,14 +248,38 @@ func HTTPProbe(opts HTTPOptions) Result {
 	}
 	defer resp.Body.Close()
 
-	bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-	bodySnippet := string(bodyBytes)
+	fullBody, snippetBytes, onWireBytes, bodyErr := readBody(resp, opts.MaxBodyBytes, opts.DecodeBody, opts.SaveBodyPath)
+	bodySnippet := string(snippetBytes)
 	transferDuration := time.Since(gotFirstByte)
 
+	// Cumulative httpstat-style checkpoints for the final hop, relative to
+	// the overall request start rather than to the hop's own phase starts.
+	nameLookupAt := dnsDoneAt
+	if nameLookupAt.IsZero() {
+		nameLookupAt = start
+	}
+	connectAt := connectDoneAt
+	if connectAt.IsZero() {
+		connectAt = nameLookupAt
+	}
+	pretransferAt := tlsDoneAt
+	if pretransferAt.IsZero() {
+		pretransferAt = connectAt
+	}
+	startTransferAt := gotFirstByte
+	if startTransferAt.IsZero() {
+		startTransferAt = pretransferAt
+	}
+
+	bodySum := sha256.Sum256(fullBody)
+
 	details := map[string]any{
 		"response_headers":     resp.Header,
 		"body_snippet":         bodySnippet,
 		"content_length":       resp.ContentLength,
+		"decoded_length":       len(fullBody),
+		"body_bytes_read":      onWireBytes,
+		"body_sha256":          hex.EncodeToString(bodySum[:]),
 		"protocol":             resp.Proto,
 		"compressed":           strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") || strings.Contains(resp.Header.Get("Content-Encoding"), "br"),
 		"dns_lookup_ms":        float64(dnsDuration.Milliseconds()),
@@ -101,18 +288,59 @@ func HTTPProbe(opts HTTPOptions) Result {
 		"server_processing_ms": float64(waitDuration.Milliseconds()),
 		"content_transfer_ms":  float64(transferDuration.Milliseconds()),
 		"total_time_ms":        float64(totalDuration.Milliseconds()),
+		"timeline": map[string]float64{
+			"name_lookup_ms":   float64(nameLookupAt.Sub(start).Milliseconds()),
+			"connect_ms":       float64(connectAt.Sub(start).Milliseconds()),
+			"pretransfer_ms":   float64(pretransferAt.Sub(start).Milliseconds()),
+			"starttransfer_ms": float64(startTransferAt.Sub(start).Milliseconds()),
+			"total_ms":         float64(totalDuration.Milliseconds()),
+		},
+		"final_url": resp.Request.URL.String(),
+	}
+	if tlsInfo != nil {
+		details["tls"] = tlsInfo
+	}
+	if opts.FollowRedirects {
+		details["redirect_chain"] = hops
 	}
 
 	var expectErr string
 	if opts.ExpectStatus != 0 && resp.StatusCode != opts.ExpectStatus {
 		expectErr = fmt.Sprintf("expect status %d, got %d", opts.ExpectStatus, resp.StatusCode)
 	}
-	if opts.ExpectContains != "" && !strings.Contains(bodySnippet, opts.ExpectContains) {
+	if opts.ExpectContains != "" && !strings.Contains(string(fullBody), opts.ExpectContains) {
 		if expectErr != "" {
 			expectErr += "; "
 		}
 		expectErr += "response not contains expected substring"
 	}
+	if tlsErr != nil {
+		if expectErr != "" {
+			expectErr += "; "
+		}
+		expectErr += tlsErr.Error()
+	}
+	if bodyErr != nil {
+		if expectErr != "" {
+			expectErr += "; "
+		}
+		expectErr += fmt.Sprintf("read body failed: %v", bodyErr)
+	}
+
+	assertionResults := evaluateAssertions(opts, resp, fullBody, totalDuration, tlsInfo)
+	details["assertions"] = assertionResults
+	failedAssertions := 0
+	for _, ar := range assertionResults {
+		if !ar.Pass {
+			failedAssertions++
+		}
+	}
+	if failedAssertions > 0 {
+		if expectErr != "" {
+			expectErr += "; "
+		}
+		expectErr += fmt.Sprintf("%d assertion(s) failed", failedAssertions)
+	}
 
 	success := expectErr == ""
 
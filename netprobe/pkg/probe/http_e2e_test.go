@@ -0,0 +1,110 @@
+package probe
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPProbeDecodesGzipBody(t *testing.T) {
+	want := strings.Repeat("hello, decoded world ", 150)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(want))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	result := HTTPProbe(HTTPOptions{URL: server.URL, DecodeBody: true})
+	if !result.Success {
+		t.Fatalf("probe failed: %+v", result)
+	}
+
+	if snippet, _ := result.Details["body_snippet"].(string); snippet != want {
+		t.Errorf("body_snippet = %q, want %q (DisableCompression must stay on so readBody controls gzip decoding)", snippet, want)
+	}
+	compressed, _ := result.Details["compressed"].(bool)
+	if !compressed {
+		t.Error("compressed = false, want true: Content-Encoding: gzip should survive to HTTPProbe")
+	}
+	onWireBytes, _ := result.Details["body_bytes_read"].(int64)
+	if int(onWireBytes) >= len(want) {
+		t.Errorf("body_bytes_read = %d, want fewer bytes than the %d-byte decoded body (it should count compressed on-wire bytes)", onWireBytes, len(want))
+	}
+}
+
+func TestHTTPProbeLeavesGzipBodyOpaqueWithoutDecodeBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte("plain text"))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	result := HTTPProbe(HTTPOptions{URL: server.URL, DecodeBody: false})
+	if !result.Success {
+		t.Fatalf("probe failed: %+v", result)
+	}
+
+	snippet, _ := result.Details["body_snippet"].(string)
+	if bytes.Contains([]byte(snippet), []byte("plain text")) {
+		t.Errorf("body_snippet = %q, expected raw gzip bytes since DecodeBody is false", snippet)
+	}
+}
+
+func TestHTTPProbeRecordsRedirectChain(t *testing.T) {
+	finalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer finalServer.Close()
+
+	redirectServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, finalServer.URL, http.StatusFound)
+	}))
+	defer redirectServer.Close()
+
+	result := HTTPProbe(HTTPOptions{URL: redirectServer.URL, FollowRedirects: true})
+	if !result.Success {
+		t.Fatalf("probe failed: %+v", result)
+	}
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d (redirects should be followed by default)", result.StatusCode, http.StatusOK)
+	}
+
+	hops, ok := result.Details["redirect_chain"].([]HopStat)
+	if !ok || len(hops) != 1 {
+		t.Fatalf("redirect_chain = %#v, want exactly one recorded hop", result.Details["redirect_chain"])
+	}
+	if hops[0].StatusCode != http.StatusFound {
+		t.Errorf("hop[0].StatusCode = %d, want %d", hops[0].StatusCode, http.StatusFound)
+	}
+	if result.Details["final_url"] != finalServer.URL {
+		t.Errorf("final_url = %v, want %v", result.Details["final_url"], finalServer.URL)
+	}
+}
+
+func TestHTTPProbeFollowsRedirectsEvenWithoutFlag(t *testing.T) {
+	finalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer finalServer.Close()
+
+	redirectServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, finalServer.URL, http.StatusFound)
+	}))
+	defer redirectServer.Close()
+
+	result := HTTPProbe(HTTPOptions{URL: redirectServer.URL})
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d: redirects must still be followed by default when FollowRedirects is unset", result.StatusCode, http.StatusOK)
+	}
+	if _, ok := result.Details["redirect_chain"]; ok {
+		t.Error("redirect_chain should not be reported when FollowRedirects is false")
+	}
+}
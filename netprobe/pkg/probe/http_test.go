@@ -0,0 +1,68 @@
+package probe
+
+import (
+	"testing"
+)
+
+func TestBuildTransportForceHTTP(t *testing.T) {
+	cases := []struct {
+		name      string
+		forceHTTP string
+		wantErr   bool
+	}{
+		{name: "auto", forceHTTP: "auto", wantErr: false},
+		{name: "empty defaults to auto", forceHTTP: "", wantErr: false},
+		{name: "1.1 disables ALPN h2", forceHTTP: "1.1", wantErr: false},
+		{name: "2 configures http2", forceHTTP: "2", wantErr: false},
+		{name: "unknown value errors", forceHTTP: "3", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport, err := buildTransport(HTTPOptions{ForceHTTP: tc.forceHTTP})
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for ForceHTTP %q, got nil", tc.forceHTTP)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for ForceHTTP %q: %v", tc.forceHTTP, err)
+			}
+			if tc.forceHTTP == "1.1" && transport.TLSNextProto == nil {
+				t.Fatalf("expected TLSNextProto to be set to disable h2 when ForceHTTP=1.1")
+			}
+		})
+	}
+}
+
+func TestBuildTransportInvalidCACert(t *testing.T) {
+	_, err := buildTransport(HTTPOptions{TLS: TLSOptions{CACertPEM: "not a cert"}})
+	if err == nil {
+		t.Fatal("expected error for invalid CACertPEM, got nil")
+	}
+}
+
+func TestBuildTransportInvalidClientKeyPair(t *testing.T) {
+	_, err := buildTransport(HTTPOptions{TLS: TLSOptions{ClientCertPEM: "bad", ClientKeyPEM: "bad"}})
+	if err == nil {
+		t.Fatal("expected error for invalid client keypair, got nil")
+	}
+}
+
+func TestTLSVersionName(t *testing.T) {
+	cases := map[uint16]string{
+		0x0301: "TLS1.0",
+		0x0302: "TLS1.1",
+		0x0303: "TLS1.2",
+		0x0304: "TLS1.3",
+	}
+	for version, want := range cases {
+		if got := tlsVersionName(version); got != want {
+			t.Errorf("tlsVersionName(0x%04x) = %q, want %q", version, got, want)
+		}
+	}
+	if got := tlsVersionName(0x9999); got == "" {
+		t.Errorf("expected a non-empty fallback name for an unknown version, got %q", got)
+	}
+}
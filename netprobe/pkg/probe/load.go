@@ -0,0 +1,187 @@
+package probe
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// loadSample is one completed request's outcome, collected by a worker and
+// folded into the final LoadResult by the aggregator.
+type loadSample struct {
+	latency    time.Duration
+	statusCode int
+	bytes      int64
+	err        error
+}
+
+// HTTPLoadProbe issues opts.Requests requests across opts.Concurrency
+// workers (round-robining over opts.URLs), optionally throttled to opts.QPS,
+// and returns aggregate latency/status/error statistics. It's meant for
+// smoke-level load tests and SLO validation, not single-shot diagnostics —
+// use HTTPProbe for those.
+func HTTPLoadProbe(opts HTTPLoadOptions) LoadResult {
+	toolName := opts.Tool
+	if toolName == "" {
+		toolName = "network.http_load"
+	}
+	if len(opts.URLs) == 0 {
+		return LoadResult{Tool: toolName, Errors: map[string]int{"no URLs configured": 1}}
+	}
+	if opts.TimeoutSec <= 0 {
+		opts.TimeoutSec = 15
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.Requests <= 0 {
+		opts.Requests = len(opts.URLs)
+	}
+	method := strings.ToUpper(opts.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	transport, err := buildTransport(HTTPOptions{TLS: opts.TLS})
+	if err != nil {
+		return LoadResult{Tool: toolName, Errors: map[string]int{err.Error(): 1}}
+	}
+	transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	transport.DisableKeepAlives = opts.DisableKeepAlives
+
+	client := &http.Client{
+		Timeout:   time.Duration(opts.TimeoutSec) * time.Second,
+		Transport: transport,
+	}
+
+	var throttle <-chan time.Time
+	if opts.QPS > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.QPS))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	jobs := make(chan int, opts.Requests)
+	for i := 0; i < opts.Requests; i++ {
+		jobs <- i
+	}
+	close(jobs)
+
+	samples := make(chan loadSample, opts.Requests)
+	var wg sync.WaitGroup
+	wg.Add(opts.Concurrency)
+	for w := 0; w < opts.Concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if throttle != nil {
+					<-throttle
+				}
+				samples <- doLoadRequest(client, method, opts, opts.URLs[i%len(opts.URLs)])
+			}
+		}()
+	}
+
+	start := time.Now()
+	go func() {
+		wg.Wait()
+		close(samples)
+	}()
+
+	result := LoadResult{
+		Tool:        toolName,
+		StatusCodes: map[int]int{},
+		Errors:      map[string]int{},
+	}
+	latencies := make([]float64, 0, opts.Requests)
+
+	for s := range samples {
+		result.TotalRequests++
+		if s.err != nil {
+			result.Failures++
+			result.Errors[s.err.Error()]++
+			continue
+		}
+		result.Successes++
+		result.StatusCodes[s.statusCode]++
+		result.BytesTransferred += s.bytes
+		latencies = append(latencies, float64(s.latency.Milliseconds()))
+	}
+
+	result.DurationMs = float64(time.Since(start).Milliseconds())
+	if result.DurationMs > 0 {
+		result.ThroughputRPS = float64(result.TotalRequests) / (result.DurationMs / 1000)
+	}
+	populateLatencyStats(&result, latencies)
+
+	return result
+}
+
+// doLoadRequest performs a single request for HTTPLoadProbe and turns it
+// into a loadSample, never panicking on transport errors.
+func doLoadRequest(client *http.Client, method string, opts HTTPLoadOptions, url string) loadSample {
+	var bodyReader io.Reader
+	if opts.Body != "" {
+		bodyReader = bytes.NewBufferString(opts.Body)
+	}
+
+	req, err := http.NewRequest(method, url, bodyReader)
+	if err != nil {
+		return loadSample{err: err}
+	}
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return loadSample{latency: latency, err: err}
+	}
+	defer resp.Body.Close()
+
+	n, _ := io.Copy(io.Discard, resp.Body)
+	return loadSample{latency: latency, statusCode: resp.StatusCode, bytes: n}
+}
+
+// populateLatencyStats fills in the min/avg/median/p95/p99/max fields of
+// result from the (unsorted) per-request latencies, in milliseconds.
+func populateLatencyStats(result *LoadResult, latencies []float64) {
+	if len(latencies) == 0 {
+		return
+	}
+	sort.Float64s(latencies)
+
+	var sum float64
+	for _, l := range latencies {
+		sum += l
+	}
+
+	result.MinLatencyMs = latencies[0]
+	result.MaxLatencyMs = latencies[len(latencies)-1]
+	result.AvgLatencyMs = sum / float64(len(latencies))
+	result.MedianLatencyMs = percentile(latencies, 50)
+	result.P95LatencyMs = percentile(latencies, 95)
+	result.P99LatencyMs = percentile(latencies, 99)
+}
+
+// percentile returns the p-th percentile (0-100) of a sorted slice using
+// linear interpolation between the two closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := (p / 100) * float64(len(sorted)-1)
+	lo := int(rank)
+	hi := lo + 1
+	if hi >= len(sorted) {
+		return sorted[lo]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + frac*(sorted[hi]-sorted[lo])
+}
@@ -0,0 +1,55 @@
+package probe
+
+import "testing"
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40, 50}
+
+	cases := []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 10},
+		{p: 50, want: 30},
+		{p: 100, want: 50},
+	}
+	for _, tc := range cases {
+		if got := percentile(sorted, tc.p); got != tc.want {
+			t.Errorf("percentile(%v, %v) = %v, want %v", sorted, tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestPercentileSingleSample(t *testing.T) {
+	if got := percentile([]float64{42}, 95); got != 42 {
+		t.Errorf("percentile of a single sample = %v, want 42", got)
+	}
+}
+
+func TestPopulateLatencyStats(t *testing.T) {
+	var result LoadResult
+	populateLatencyStats(&result, []float64{100, 200, 300, 400, 500})
+
+	if result.MinLatencyMs != 100 {
+		t.Errorf("MinLatencyMs = %v, want 100", result.MinLatencyMs)
+	}
+	if result.MaxLatencyMs != 500 {
+		t.Errorf("MaxLatencyMs = %v, want 500", result.MaxLatencyMs)
+	}
+	if result.AvgLatencyMs != 300 {
+		t.Errorf("AvgLatencyMs = %v, want 300", result.AvgLatencyMs)
+	}
+	if result.MedianLatencyMs != 300 {
+		t.Errorf("MedianLatencyMs = %v, want 300", result.MedianLatencyMs)
+	}
+}
+
+func TestHTTPLoadProbeRejectsEmptyURLs(t *testing.T) {
+	result := HTTPLoadProbe(HTTPLoadOptions{Requests: 5})
+	if result.TotalRequests != 0 {
+		t.Errorf("TotalRequests = %d, want 0 for an empty URL list", result.TotalRequests)
+	}
+	if len(result.Errors) == 0 {
+		t.Error("expected Errors to be populated for an empty URL list")
+	}
+}
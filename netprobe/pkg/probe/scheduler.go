@@ -0,0 +1,299 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Target is one endpoint the Scheduler probes on a recurring basis.
+type Target struct {
+	ID       string
+	Options  HTTPOptions
+	Interval time.Duration
+
+	// LatencySLOMs, if nonzero, is the rolling p95 latency (over the last
+	// LatencyWindow results) above which EventLatencySLOBreach fires.
+	LatencySLOMs float64
+
+	// LatencyWindow is how many recent results feed the rolling p95 used
+	// for SLO breach detection. Zero defaults to 20.
+	LatencyWindow int
+}
+
+// Event types emitted by the Scheduler when an observable property of a
+// target changes between consecutive probes.
+const (
+	EventStatusChange     = "status_change"
+	EventLatencySLOBreach = "latency_slo_breach"
+	EventTLSCertChange    = "tls_cert_change"
+	EventBodyDrift        = "body_drift"
+	EventALPNDowngrade    = "alpn_downgrade"
+)
+
+// Event is a single observable change the Scheduler detected for a target.
+type Event struct {
+	TargetID  string `json:"target_id"`
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"` // unix seconds
+	Result    Result `json:"result"`
+}
+
+// Scheduler runs HTTPProbe against a set of Targets on their configured
+// intervals, records every Result to a Store, and emits an Event whenever a
+// target's status code, TLS fingerprint, or body hash changes from what was
+// last observed, its ALPN protocol is downgraded, or its rolling p95 latency
+// exceeds the target's LatencySLOMs. It turns the otherwise
+// one-shot HTTPProbe into a continuous synthetic-monitoring component.
+type Scheduler struct {
+	store  Store
+	events chan Event
+
+	mu      sync.Mutex
+	targets map[string]*targetState
+
+	eventLogMu  sync.Mutex
+	eventLog    []Event
+	eventLogCap int
+}
+
+// targetState is the Scheduler's private bookkeeping for one Target: its
+// config, the goroutine cancel func, and what was last observed (for change
+// detection).
+type targetState struct {
+	target Target
+	cancel context.CancelFunc
+
+	lastStatusCode  int
+	lastTLSFp       string
+	lastALPN        string
+	lastBodySHA256  string
+	recentLatencyMs []float64
+}
+
+// NewScheduler returns a Scheduler backed by store, with an events channel
+// buffered to hold bufferedEvents before callers must start consuming it.
+func NewScheduler(store Store, bufferedEvents int) *Scheduler {
+	return &Scheduler{
+		store:       store,
+		events:      make(chan Event, bufferedEvents),
+		targets:     map[string]*targetState{},
+		eventLogCap: 500,
+	}
+}
+
+// RecentEvents returns up to the last eventLogCap events published across
+// all targets, oldest first, for callers (like the HTTP API) that want a
+// snapshot rather than a channel subscription.
+func (s *Scheduler) RecentEvents() []Event {
+	s.eventLogMu.Lock()
+	defer s.eventLogMu.Unlock()
+
+	out := make([]Event, len(s.eventLog))
+	copy(out, s.eventLog)
+	return out
+}
+
+// Events returns the channel Scheduler publishes Events to. Callers should
+// keep draining it; a full buffer causes the scheduler's probe loop for the
+// offending target to block until space frees up.
+func (s *Scheduler) Events() <-chan Event {
+	return s.events
+}
+
+// AddTarget registers t and starts probing it on its own goroutine. Calling
+// AddTarget again with the same ID replaces the existing target.
+func (s *Scheduler) AddTarget(ctx context.Context, t Target) error {
+	if t.ID == "" {
+		return fmt.Errorf("target ID must not be empty")
+	}
+	if t.Interval <= 0 {
+		return fmt.Errorf("target %q: Interval must be positive", t.ID)
+	}
+
+	s.RemoveTarget(t.ID)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	state := &targetState{target: t, cancel: cancel}
+
+	s.mu.Lock()
+	s.targets[t.ID] = state
+	s.mu.Unlock()
+
+	go s.runTarget(runCtx, state)
+	return nil
+}
+
+// RemoveTarget stops probing the target with the given ID, if any.
+func (s *Scheduler) RemoveTarget(id string) {
+	s.mu.Lock()
+	state, ok := s.targets[id]
+	if ok {
+		delete(s.targets, id)
+	}
+	s.mu.Unlock()
+
+	if ok {
+		state.cancel()
+	}
+}
+
+// Targets returns the IDs of currently registered targets.
+func (s *Scheduler) Targets() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.targets))
+	for id := range s.targets {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (s *Scheduler) runTarget(ctx context.Context, state *targetState) {
+	ticker := time.NewTicker(state.target.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.probeOnce(state)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) probeOnce(state *targetState) {
+	result := HTTPProbe(state.target.Options)
+	now := time.Now()
+
+	if err := s.store.Append(state.target.ID, HistoryEntry{Timestamp: now.Unix(), Result: result}); err != nil {
+		s.publish(Event{
+			TargetID:  state.target.ID,
+			Type:      "store_error",
+			Message:   err.Error(),
+			Timestamp: now.Unix(),
+			Result:    result,
+		})
+	}
+
+	for _, ev := range detectChanges(state, result, now) {
+		s.publish(ev)
+	}
+}
+
+// publish records ev in the recent-events log and sends it on the events
+// channel, blocking if the channel's buffer is full (backpressure is
+// preferable to silently dropping a detected change).
+func (s *Scheduler) publish(ev Event) {
+	s.eventLogMu.Lock()
+	s.eventLog = append(s.eventLog, ev)
+	if len(s.eventLog) > s.eventLogCap {
+		s.eventLog = s.eventLog[len(s.eventLog)-s.eventLogCap:]
+	}
+	s.eventLogMu.Unlock()
+
+	s.events <- ev
+}
+
+// detectChanges compares result against state's last-observed values,
+// updates state in place, and returns the Events the comparison produced.
+func detectChanges(state *targetState, result Result, now time.Time) []Event {
+	var events []Event
+	ts := now.Unix()
+
+	if state.lastStatusCode != 0 && result.StatusCode != 0 && result.StatusCode != state.lastStatusCode {
+		events = append(events, Event{
+			TargetID: state.target.ID, Type: EventStatusChange, Timestamp: ts, Result: result,
+			Message: fmt.Sprintf("status changed %d -> %d", state.lastStatusCode, result.StatusCode),
+		})
+	}
+	if result.StatusCode != 0 {
+		state.lastStatusCode = result.StatusCode
+	}
+
+	if tlsDetails, ok := result.Details["tls"].(map[string]any); ok {
+		if fp, ok := tlsDetails["sha256_fingerprint"].(string); ok {
+			if state.lastTLSFp != "" && fp != state.lastTLSFp {
+				events = append(events, Event{
+					TargetID: state.target.ID, Type: EventTLSCertChange, Timestamp: ts, Result: result,
+					Message: fmt.Sprintf("certificate fingerprint changed %s -> %s", state.lastTLSFp, fp),
+				})
+			}
+			state.lastTLSFp = fp
+		}
+		if alpn, ok := tlsDetails["alpn"].(string); ok {
+			if state.lastALPN != "" && isALPNDowngrade(state.lastALPN, alpn) {
+				events = append(events, Event{
+					TargetID: state.target.ID, Type: EventALPNDowngrade, Timestamp: ts, Result: result,
+					Message: fmt.Sprintf("ALPN downgraded %s -> %s", state.lastALPN, alpn),
+				})
+			}
+			state.lastALPN = alpn
+		}
+	}
+
+	if sha, ok := bodySHA256(result.Details); ok {
+		if state.lastBodySHA256 != "" && sha != state.lastBodySHA256 {
+			events = append(events, Event{
+				TargetID: state.target.ID, Type: EventBodyDrift, Timestamp: ts, Result: result,
+				Message: fmt.Sprintf("body sha256 changed %s -> %s", state.lastBodySHA256, sha),
+			})
+		}
+		state.lastBodySHA256 = sha
+	}
+
+	window := state.target.LatencyWindow
+	if window <= 0 {
+		window = 20
+	}
+	state.recentLatencyMs = append(state.recentLatencyMs, result.LatencyMs)
+	if len(state.recentLatencyMs) > window {
+		state.recentLatencyMs = state.recentLatencyMs[len(state.recentLatencyMs)-window:]
+	}
+	if state.target.LatencySLOMs > 0 && len(state.recentLatencyMs) >= window {
+		p95 := rollingP95(state.recentLatencyMs)
+		if p95 > state.target.LatencySLOMs {
+			events = append(events, Event{
+				TargetID: state.target.ID, Type: EventLatencySLOBreach, Timestamp: ts, Result: result,
+				Message: fmt.Sprintf("rolling p95 latency %.1fms exceeds SLO %.1fms", p95, state.target.LatencySLOMs),
+			})
+		}
+	}
+
+	return events
+}
+
+// bodySHA256 reads the body hash HTTPProbe always computes into
+// Result.Details["body_sha256"], independent of whatever Assertions (if
+// any) the target happens to configure.
+func bodySHA256(details map[string]any) (string, bool) {
+	sha, ok := details["body_sha256"].(string)
+	return sha, ok
+}
+
+// isALPNDowngrade reports whether protocol moved from a newer negotiated
+// protocol to an older one (h2 -> http/1.1, or any protocol -> none).
+// Protocols this ranking doesn't know about are treated as "can't tell" so
+// an unfamiliar ALPN value never counts as a downgrade.
+func isALPNDowngrade(from, to string) bool {
+	rank := map[string]int{"": 0, "http/1.1": 1, "h2": 2}
+	fromRank, fromKnown := rank[from]
+	toRank, toKnown := rank[to]
+	if !fromKnown || !toKnown {
+		return false
+	}
+	return toRank < fromRank
+}
+
+// rollingP95 returns the 95th percentile of samples without mutating it.
+func rollingP95(samples []float64) float64 {
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	return percentile(sorted, 95)
+}
@@ -0,0 +1,82 @@
+package probe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsALPNDowngrade(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     bool
+	}{
+		{from: "h2", to: "http/1.1", want: true},
+		{from: "h2", to: "", want: true},
+		{from: "http/1.1", to: "h2", want: false},
+		{from: "h2", to: "h2", want: false},
+		{from: "h2", to: "h3", want: false}, // unknown protocol: can't tell, not a downgrade
+		{from: "h3", to: "h2", want: false}, // unknown "from": can't tell either
+	}
+	for _, tc := range cases {
+		if got := isALPNDowngrade(tc.from, tc.to); got != tc.want {
+			t.Errorf("isALPNDowngrade(%q, %q) = %v, want %v", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestRollingP95(t *testing.T) {
+	samples := []float64{10, 20, 30, 40, 50}
+	got := rollingP95(samples)
+	if got != percentile([]float64{10, 20, 30, 40, 50}, 95) {
+		t.Errorf("rollingP95(%v) = %v, want percentile(..., 95)", samples, got)
+	}
+	// rollingP95 must not mutate its input's order.
+	if samples[0] != 10 || samples[4] != 50 {
+		t.Errorf("rollingP95 mutated its input: %v", samples)
+	}
+}
+
+func TestDetectChangesStatusChange(t *testing.T) {
+	state := &targetState{target: Target{ID: "svc"}, lastStatusCode: 200}
+	result := Result{StatusCode: 500, Details: map[string]any{}}
+
+	events := detectChanges(state, result, time.Now())
+	if !containsEventType(events, EventStatusChange) {
+		t.Errorf("expected a status_change event, got %+v", events)
+	}
+	if state.lastStatusCode != 500 {
+		t.Errorf("lastStatusCode = %d, want 500 after detectChanges", state.lastStatusCode)
+	}
+}
+
+func TestDetectChangesNoStatusChangeOnFirstProbe(t *testing.T) {
+	state := &targetState{target: Target{ID: "svc"}}
+	result := Result{StatusCode: 200, Details: map[string]any{}}
+
+	events := detectChanges(state, result, time.Now())
+	if containsEventType(events, EventStatusChange) {
+		t.Errorf("expected no status_change event on the first probe, got %+v", events)
+	}
+}
+
+func TestDetectChangesBodyDrift(t *testing.T) {
+	state := &targetState{target: Target{ID: "svc"}, lastBodySHA256: "aaa"}
+	result := Result{Details: map[string]any{"body_sha256": "bbb"}}
+
+	events := detectChanges(state, result, time.Now())
+	if !containsEventType(events, EventBodyDrift) {
+		t.Errorf("expected a body_drift event, got %+v", events)
+	}
+	if state.lastBodySHA256 != "bbb" {
+		t.Errorf("lastBodySHA256 = %q, want %q", state.lastBodySHA256, "bbb")
+	}
+}
+
+func containsEventType(events []Event, eventType string) bool {
+	for _, ev := range events {
+		if ev.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
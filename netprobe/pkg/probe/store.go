@@ -0,0 +1,76 @@
+package probe
+
+import (
+	"sync"
+)
+
+// HistoryEntry pairs a Result with the time it was recorded, since Result
+// itself carries no timestamp.
+type HistoryEntry struct {
+	Timestamp int64  `json:"timestamp"` // unix seconds
+	Result    Result `json:"result"`
+}
+
+// Store is the persistence layer behind Scheduler: it keeps a rolling
+// history of Results per target ID. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	Append(targetID string, entry HistoryEntry) error
+	History(targetID string, limit int) ([]HistoryEntry, error)
+	TargetIDs() ([]string, error)
+}
+
+// MemoryStore is an in-memory Store, suitable for a single-process
+// scheduler or for tests. History per target is capped at MaxPerTarget
+// entries (oldest dropped first); zero means unbounded.
+type MemoryStore struct {
+	MaxPerTarget int
+
+	mu      sync.Mutex
+	history map[string][]HistoryEntry
+}
+
+// NewMemoryStore returns a MemoryStore capping each target's history at
+// maxPerTarget entries (0 for unbounded).
+func NewMemoryStore(maxPerTarget int) *MemoryStore {
+	return &MemoryStore{
+		MaxPerTarget: maxPerTarget,
+		history:      map[string][]HistoryEntry{},
+	}
+}
+
+func (m *MemoryStore) Append(targetID string, entry HistoryEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := append(m.history[targetID], entry)
+	if m.MaxPerTarget > 0 && len(h) > m.MaxPerTarget {
+		h = h[len(h)-m.MaxPerTarget:]
+	}
+	m.history[targetID] = h
+	return nil
+}
+
+func (m *MemoryStore) History(targetID string, limit int) ([]HistoryEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := m.history[targetID]
+	if limit > 0 && len(h) > limit {
+		h = h[len(h)-limit:]
+	}
+	out := make([]HistoryEntry, len(h))
+	copy(out, h)
+	return out, nil
+}
+
+func (m *MemoryStore) TargetIDs() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(m.history))
+	for id := range m.history {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,121 @@
+package probe
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore persists probe history to a SQLite database, for schedulers
+// that need history to survive a restart. Results are stored as JSON
+// rather than normalized into columns, since Result.Details is an open map
+// whose shape varies by probe type.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (and migrates) a SQLite database at path. Use
+// ":memory:" for a throwaway store with the same interface as
+// MemoryStore but exercised through the SQL path.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite store: %w", err)
+	}
+	// database/sql pools connections; a second connection to ":memory:"
+	// would see an empty, unmigrated database, and SQLite doesn't support
+	// concurrent writers anyway. Pin to one.
+	db.SetMaxOpenConns(1)
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS probe_history (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	target_id TEXT NOT NULL,
+	timestamp INTEGER NOT NULL,
+	result    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_probe_history_target ON probe_history(target_id, id);
+`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate sqlite store: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) Append(targetID string, entry HistoryEntry) error {
+	encoded, err := json.Marshal(entry.Result)
+	if err != nil {
+		return fmt.Errorf("encode result: %w", err)
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO probe_history (target_id, timestamp, result) VALUES (?, ?, ?)`,
+		targetID, entry.Timestamp, string(encoded),
+	)
+	if err != nil {
+		return fmt.Errorf("append history: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) History(targetID string, limit int) ([]HistoryEntry, error) {
+	query := `SELECT timestamp, result FROM probe_history WHERE target_id = ? ORDER BY id DESC`
+	args := []any{targetID}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var ts int64
+		var raw string
+		if err := rows.Scan(&ts, &raw); err != nil {
+			return nil, fmt.Errorf("scan history row: %w", err)
+		}
+		var result Result
+		if err := json.Unmarshal([]byte(raw), &result); err != nil {
+			return nil, fmt.Errorf("decode history row: %w", err)
+		}
+		entries = append(entries, HistoryEntry{Timestamp: ts, Result: result})
+	}
+
+	// Rows came back newest-first; History's contract (matching
+	// MemoryStore) is oldest-first.
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	return entries, rows.Err()
+}
+
+func (s *SQLiteStore) TargetIDs() ([]string, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT target_id FROM probe_history`)
+	if err != nil {
+		return nil, fmt.Errorf("query target ids: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan target id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,184 @@
+package probe
+
+// HTTPOptions configures a single HTTPProbe invocation.
+type HTTPOptions struct {
+	Tool       string
+	URL        string
+	Method     string
+	Headers    map[string]string
+	Body       string
+	TimeoutSec int
+
+	ExpectStatus   int
+	ExpectContains string
+
+	// TLS customizes the transport's TLS handshake. Zero value keeps the
+	// platform defaults (verify against the system root pool).
+	TLS TLSOptions
+
+	// ForceHTTP controls the negotiated protocol version: "1.1" disables H2
+	// entirely, "2" configures H2 via http2.ConfigureTransport (which still
+	// offers http/1.1 over ALPN, so a server without H2 is reached over
+	// HTTP/1.1), and "auto" (the default) lets ALPN negotiate normally.
+	ForceHTTP string
+
+	// FollowRedirects surfaces a HopStat per hop in
+	// Result.Details["redirect_chain"]. Redirects are always followed
+	// (matching the zero-value http.Client's default policy of following
+	// up to 10 hops) regardless of this flag; it only toggles whether the
+	// per-hop history is reported.
+	FollowRedirects bool
+
+	// Assertions are evaluated against the response in addition to
+	// ExpectStatus/ExpectContains, letting HTTPProbe act as a general
+	// synthetic monitor. Each one is reported individually in
+	// Result.Details["assertions"].
+	Assertions []Assertion
+
+	// MaxBodyBytes caps how much of the (decoded) body is kept as
+	// Result.Details["body_snippet"]. Zero uses the default of 4096; -1
+	// keeps the whole body.
+	MaxBodyBytes int
+
+	// DecodeBody transparently decompresses a gzip/brotli response body
+	// before snippetting and hashing it, instead of treating the
+	// Content-Encoding as opaque.
+	DecodeBody bool
+
+	// SaveBodyPath, if set, writes the full (decoded) body to this path
+	// for offline diffing.
+	SaveBodyPath string
+}
+
+// Assertion is a single check evaluated against an HTTPProbe response.
+// Which of Header, Path, and Value apply depends on Type:
+//
+//	status_in              Value is a comma-separated list of status codes, e.g. "200,201,204"
+//	header_equals          Header is the header name, Value the expected value
+//	header_matches         Header is the header name, Value a regexp pattern
+//	body_regex             Value is a regexp pattern matched against the body
+//	body_jsonpath          Path is a dot-separated path (e.g. "data.items.0.id"), Value the expected string
+//	latency_below_ms       Value is a threshold in milliseconds
+//	tls_expires_after_days Value is a minimum number of days until certificate expiry
+//	body_sha256_equals     Value is the expected lowercase hex SHA-256 of the body
+type Assertion struct {
+	Type   string
+	Header string
+	Path   string
+	Value  string
+}
+
+// AssertionResult is the outcome of evaluating a single Assertion.
+type AssertionResult struct {
+	Type     string `json:"type"`
+	Pass     bool   `json:"pass"`
+	Observed string `json:"observed"`
+	Error    string `json:"error,omitempty"`
+}
+
+// HopStat is the phase breakdown for a single hop in a redirect chain.
+type HopStat struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+
+	DNSLookupMs        float64 `json:"dns_lookup_ms"`
+	TCPConnectionMs    float64 `json:"tcp_connection_ms"`
+	TLSHandshakeMs     float64 `json:"tls_handshake_ms"`
+	ServerProcessingMs float64 `json:"server_processing_ms"`
+	ContentTransferMs  float64 `json:"content_transfer_ms"`
+}
+
+// TLSOptions customizes the TLS handshake HTTPProbe performs, so the
+// probe can reach endpoints with self-signed certs, mutual TLS, a
+// pinned leaf fingerprint, or a forced SNI/min-max version.
+type TLSOptions struct {
+	InsecureSkipVerify bool
+
+	// CACertPEM, if set, replaces the system root pool with a single
+	// PEM-encoded CA bundle.
+	CACertPEM string
+
+	// ClientCertPEM/ClientKeyPEM enable mutual TLS when both are set.
+	ClientCertPEM string
+	ClientKeyPEM  string
+
+	// ServerName overrides SNI and the hostname used for certificate
+	// verification.
+	ServerName string
+
+	// MinVersion/MaxVersion are tls.VersionTLS* constants. Zero means
+	// "use the crypto/tls default".
+	MinVersion uint16
+	MaxVersion uint16
+
+	// PinnedSHA256, if set, is the lowercase hex SHA-256 fingerprint of
+	// the expected leaf certificate. The probe fails if the server
+	// presents a different leaf.
+	PinnedSHA256 string
+}
+
+// HTTPLoadOptions configures HTTPLoadProbe, a concurrent multi-request
+// variant of HTTPProbe aimed at smoke-level load tests and SLO checks
+// rather than single-shot diagnostics.
+type HTTPLoadOptions struct {
+	Tool string
+
+	// URLs is the set of targets to probe. A single-URL load test is just
+	// URLs with one element.
+	URLs []string
+
+	Method     string
+	Headers    map[string]string
+	Body       string
+	TimeoutSec int
+	TLS        TLSOptions
+
+	// Concurrency is the number of worker goroutines issuing requests.
+	Concurrency int
+
+	// Requests is the total number of requests to issue across all
+	// workers and URLs (round-robin over URLs).
+	Requests int
+
+	// QPS throttles the aggregate request rate across all workers. Zero
+	// means unthrottled.
+	QPS float64
+
+	// MaxIdleConnsPerHost and DisableKeepAlives configure the single
+	// http.Transport shared by every worker.
+	MaxIdleConnsPerHost int
+	DisableKeepAlives   bool
+}
+
+// LoadResult is the aggregate outcome of an HTTPLoadProbe run.
+type LoadResult struct {
+	Tool          string `json:"tool"`
+	TotalRequests int    `json:"total_requests"`
+	Successes     int    `json:"successes"`
+	Failures      int    `json:"failures"`
+
+	MinLatencyMs    float64 `json:"min_latency_ms"`
+	AvgLatencyMs    float64 `json:"avg_latency_ms"`
+	MedianLatencyMs float64 `json:"median_latency_ms"`
+	P95LatencyMs    float64 `json:"p95_latency_ms"`
+	P99LatencyMs    float64 `json:"p99_latency_ms"`
+	MaxLatencyMs    float64 `json:"max_latency_ms"`
+
+	StatusCodes map[int]int    `json:"status_codes"`
+	Errors      map[string]int `json:"errors"`
+
+	ThroughputRPS    float64 `json:"throughput_rps"`
+	BytesTransferred int64   `json:"bytes_transferred"`
+	DurationMs       float64 `json:"duration_ms"`
+}
+
+// Result is the outcome of a single probe run.
+type Result struct {
+	Success    bool           `json:"success"`
+	Tool       string         `json:"tool"`
+	URL        string         `json:"url"`
+	StatusCode int            `json:"status_code"`
+	LatencyMs  float64        `json:"latency_ms"`
+	Details    map[string]any `json:"details,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}